@@ -3,9 +3,6 @@ package tucana
 import (
 	"context"
 	"fmt"
-	"github.com/garyburd/redigo/redis"
-	jsoniter "github.com/json-iterator/go"
-	localCache "github.com/patrickmn/go-cache"
 	"golang.org/x/sync/singleflight"
 	"time"
 )
@@ -22,7 +19,8 @@ const (
 	commandDel        = "DEL"
 	chanMessageFormat = "%s|%s" //key|operation
 
-	defaultExpireIn = 10 * time.Second
+	defaultExpireIn         = 10 * time.Second
+	defaultNegativeExpireIn = 1 * time.Second //空数据默认过期时间，远小于正值，避免上游短暂抖动被长期当作"不存在"
 
 	layerLocal  = 1
 	layerRemote = 2
@@ -39,16 +37,28 @@ type fetcher struct {
 type fetchFunc func() (cachedContent []byte, isEmpty bool, err error)
 
 type CacheOption struct {
-	JsonParser      jsoniter.API
 	Layer           int           //缓存层级
-	DefaultExpireIn time.Duration //默认过期时间，空数据时使用该值
+	PositiveTTL     time.Duration //真实数据的过期时间
+	NegativeTTL     time.Duration //空数据(缓存穿透哨兵值)的过期时间，默认远小于 PositiveTTL
+	TTLJitter       float64       //过期时间的抖动比例 (0~1)，避免同一秒写入的 key 同一秒集中过期
+	Remote          RemoteStore   //远程缓存的存取实现，默认基于 mgr 的 redigo 连接池
+	Codec           Codec         //序列化实现，默认保持历史行为 (jsoniter)
+	Local           LocalStore    //本地缓存的存取实现，默认为 patrickmn/go-cache
+	LocalMaxBytes   int64         //本地缓存的内存上限，仅对支持容量控制的 LocalStore 生效
+	LocalMaxEntries int64         //本地缓存的条目数上限，仅对支持容量控制的 LocalStore 生效
+
+	RefreshAheadThreshold float64       //剩余 TTL 占比低于该阈值时异步刷新，0 表示关闭
+	StaleWhileRevalidate  time.Duration //缓存过期后，仍可在该时长内返回旧值并触发异步刷新，0 表示关闭
+
+	Stats Stats //命中/未命中/拉取耗时/淘汰的观测回调，默认 no-op
 }
 
 //缓存对象
 type tCache struct {
 	option     *CacheOption
 	m          *manager
-	localCache *localCache.Cache
+	localCache LocalStore
+	staleCache LocalStore //StaleWhileRevalidate 开启时，保存过期后仍可返回的旧值
 
 	fetcher fetchFunc
 	watchC  chan alteration //key值变动的通知channel
@@ -60,14 +70,19 @@ func New() *tCache {
 		panic("Init first")
 	}
 
+	local := NewGoCacheStore(5 * time.Minute)
 	tc := &tCache{
 		option: &CacheOption{
-			JsonParser:      jsoniter.ConfigCompatibleWithStandardLibrary,
-			Layer:           layerLocal,
-			DefaultExpireIn: defaultExpireIn,
+			Layer:       layerLocal,
+			PositiveTTL: defaultExpireIn,
+			NegativeTTL: defaultNegativeExpireIn,
+			Remote:      NewRedigoStore(mgr.manager.rdsPool),
+			Codec:       DefaultCodec(),
+			Local:       local,
+			Stats:       NewNoopStats(),
 		},
 		m:          mgr.manager,
-		localCache: localCache.New(1*time.Minute, 5*time.Minute),
+		localCache: local,
 		fetcher:    nil,
 		watchC:     make(chan alteration, 10),
 		sf:         singleflight.Group{},
@@ -84,11 +99,37 @@ func (t *tCache) WithOptions(options ...Option) {
 	for _, o := range options {
 		o(t)
 	}
+
+	// staleCache is sized off the final PositiveTTL/StaleWhileRevalidate and
+	// created exactly once here rather than lazily in setStale: setStale
+	// runs concurrently from both GetOrFetch callers and refreshAsync's own
+	// goroutine, and a lazy create-on-first-use there would race.
+	if t.option.StaleWhileRevalidate > 0 && t.staleCache == nil {
+		t.staleCache = NewGoCacheStore(t.option.PositiveTTL + t.option.StaleWhileRevalidate)
+	}
 }
 
 func WithDefaultExpireIn(In time.Duration) Option {
 	return func(t *tCache) {
-		t.option.DefaultExpireIn = In
+		t.option.PositiveTTL = In
+	}
+}
+
+// WithNegativeTTL sets the expiry for the "_n" not-found sentinel, kept
+// short so a transient upstream miss doesn't get cached for as long as a
+// real value would.
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(t *tCache) {
+		t.option.NegativeTTL = ttl
+	}
+}
+
+// WithTTLJitter adds a uniform +/- fraction to every TTL so that a batch of
+// keys cached in the same second doesn't all expire in the same second
+// (cache-stampede mitigation).
+func WithTTLJitter(fraction float64) Option {
+	return func(t *tCache) {
+		t.option.TTLJitter = fraction
 	}
 }
 
@@ -98,15 +139,90 @@ func WithLayer(layer int) Option {
 	}
 }
 
+// WithRemoteStore swaps the remote cache backend, e.g. to move from redigo
+// to go-redis/v8, or onto a cluster/sentinel deployment.
+func WithRemoteStore(store RemoteStore) Option {
+	return func(t *tCache) {
+		t.option.Remote = store
+	}
+}
+
+// WithCodec swaps the serialization codec used by setLocal and
+// GetOrFetchInto, e.g. to move from jsoniter to msgpack or sonic.
+func WithCodec(codec Codec) Option {
+	return func(t *tCache) {
+		t.option.Codec = codec
+	}
+}
+
+// WithRefreshAhead enables async repopulation once a cached entry's
+// remaining TTL falls under threshold (e.g. 0.2 for the last 20%), so hot
+// keys don't pay a synchronous fetcher cost at the TTL boundary.
+func WithRefreshAhead(threshold float64) Option {
+	return func(t *tCache) {
+		t.option.RefreshAheadThreshold = threshold
+	}
+}
+
+// WithStaleWhileRevalidate lets an expired key still be served for up to
+// maxStaleness after expiry while a background fetch repopulates it.
+func WithStaleWhileRevalidate(maxStaleness time.Duration) Option {
+	return func(t *tCache) {
+		t.option.StaleWhileRevalidate = maxStaleness
+	}
+}
+
 func WithOptions(o CacheOption) Option {
 	return func(t *tCache) {
+		if o.Local == nil {
+			local, err := newBoundedLocalStore(&o)
+			if err != nil {
+				// A misconfigured bound (e.g. an implausible LocalMaxBytes/
+				// LocalMaxEntries) is a setup-time programmer error, not
+				// something to paper over with an unbounded fallback.
+				panic(fmt.Sprintf("WithOptions: building local store failed, err=%s", err))
+			}
+			o.Local = local
+		}
+		if o.Stats == nil {
+			o.Stats = NewNoopStats()
+		}
+		if o.Remote == nil {
+			o.Remote = t.option.Remote
+		}
+		if o.Codec == nil {
+			o.Codec = DefaultCodec()
+		}
+		if o.NegativeTTL <= 0 {
+			o.NegativeTTL = defaultNegativeExpireIn
+		}
 		t.option = &o
+		t.localCache = o.Local
+	}
+}
+
+// WithStats wires a Stats observer (e.g. the built-in Prometheus adapter)
+// into the cache's hit/miss/fetch/evict path.
+func WithStats(stats Stats) Option {
+	return func(t *tCache) {
+		t.option.Stats = stats
+	}
+}
+
+// WithLocalStore swaps the local cache backend, e.g. to move from
+// patrickmn/go-cache to ristretto (TinyLFU) or freecache. The store is used
+// as given: its bounds are those it was constructed with, and
+// LocalMaxBytes/LocalMaxEntries are not applied to it.
+func WithLocalStore(store LocalStore) Option {
+	return func(t *tCache) {
+		t.option.Local = store
+		t.localCache = store
 	}
 }
 
 //Storing data into cache
-func (t *tCache) store(key string, bdata []byte, layer int) error {
-	expireIn := t.option.DefaultExpireIn
+func (t *tCache) store(ctx context.Context, key string, bdata []byte, layer int) error {
+	expireIn := t.jitteredTTL(t.ttlFor(bdata))
 
 	switch layer {
 	case layerLocal:
@@ -114,10 +230,10 @@ func (t *tCache) store(key string, bdata []byte, layer int) error {
 		return nil
 	case layerRemote:
 		//just one shot, ignore if it's failed
-		_, err := t.setRemote(key, bdata, expireIn, false)
+		_, err := t.setRemote(ctx, key, bdata, expireIn, false)
 		return err
 	case layerLocal | layerRemote:
-		ok, err := t.setRemote(key, bdata, expireIn, false)
+		ok, err := t.setRemote(ctx, key, bdata, expireIn, false)
 		if err != nil {
 			return err
 		}
@@ -154,14 +270,16 @@ func (t *tCache) nil() []byte {
 }
 
 // load Fetching data from source and fill it into cache
-func (t *tCache) load(key string, fetcher fetchFunc) ([]byte, bool, error) {
+func (t *tCache) load(ctx context.Context, key string, fetcher fetchFunc) ([]byte, bool, error) {
 	//fetch data from datasource
 	//singleflight 防止数据源被压垮
 	//从数据源拉取数据
 	data, err, _ := t.sf.Do(key, func() (interface{}, error) {
 		//在本次读取新数据时，把上一次的旧数据清除，节约内存
 		t.sf.Forget(key)
+		start := time.Now()
 		data, isNil, err := t.pull(fetcher)
+		t.option.Stats.OnFetch(key, time.Since(start), err)
 		if err != nil {
 			return t.nil(), err
 		}
@@ -191,30 +309,34 @@ func (t *tCache) load(key string, fetcher fetchFunc) ([]byte, bool, error) {
 }
 
 //getting cache cascaded
-func (t *tCache) getCascade(key string, layer int, fresh bool) (bdata []byte, ok bool, err error) {
+func (t *tCache) getCascade(ctx context.Context, key string, layer int, fresh bool) (bdata []byte, ok bool, err error) {
 	switch layer {
 	case layerLocal: //从本地获取缓存
 		bdata, ok = t.getLocal(key)
+		t.reportHitMiss(layerLocal, key, ok)
 		if ok {
 			return bdata, ok, nil
 		}
 	case layerRemote: //从远程rds获取缓存
-		bdata, ok, err = t.getRemote(key)
+		bdata, ok, err = t.getRemote(ctx, key)
+		t.reportHitMiss(layerRemote, key, ok)
 		if ok || err == nil {
 			return bdata, ok, nil
 		}
 	case layerRemote | layerLocal: //先从本地获取缓存，再从远程rds获取缓存
 		bdata, ok = t.getLocal(key)
+		t.reportHitMiss(layerLocal, key, ok)
 		if ok {
 			return bdata, ok, nil
 		}
 
-		bdata, ok, err = t.getRemote(key)
+		bdata, ok, err = t.getRemote(ctx, key)
+		t.reportHitMiss(layerRemote, key, ok)
 		if ok || err == nil {
 
 			if fresh {
 				//更新本地缓存
-				t.setLocal(key, bdata, t.option.DefaultExpireIn)
+				t.setLocal(key, bdata, t.jitteredTTL(t.ttlFor(bdata)))
 			}
 
 			return bdata, ok, err
@@ -227,62 +349,57 @@ func (t *tCache) getCascade(key string, layer int, fresh bool) (bdata []byte, ok
 //设置本地缓存
 // setLocal Setting local cache
 func (t *tCache) setLocal(key string, obj interface{}, expireIn time.Duration) {
+	start := time.Now()
+	var err error
+	defer func() { t.option.Stats.OnWrite(layerLocal, key, time.Since(start), err) }()
+
 	switch obj.(type) {
 	case []byte:
 		t.localCache.Set(key, obj.([]byte), expireIn)
 		return
 	}
 
-	//w := &bytes.Buffer{}
-	//dec := gob.NewEncoder(	w)
-	//err := dec.Encode(&obj)
-	//if err != nil {
-	//	fmt.Println(fmt.Sprintf("setLocal key=%s, err=%s", key, err))
-	//	return err
-	//}
-	//t.localCache.Set(key, w.Bytes(), expireIn)
-
-	bdata, _ := t.option.JsonParser.Marshal(obj)
+	var bdata []byte
+	bdata, err = t.encode(obj)
+	if err != nil {
+		fmt.Printf("setLocal key=%s, err=%s", key, err)
+		return
+	}
 	t.localCache.Set(key, bdata, expireIn)
 	return
 }
 
 func (t *tCache) getLocal(key string) ([]byte, bool) {
 	data, ok := t.localCache.Get(key)
-	if ok {
-		if t.isNil(data.([]byte)) {
-			return nil, false
-		}
-		return data.([]byte), true
+	if !ok {
+		return nil, false
+	}
+	// A cached "_n" sentinel is a known-negative hit, same as getRemote:
+	// report it as ok=true so callers (e.g. GetOrFetchMulti, getCascade's
+	// layerLocal branch) don't treat it as a miss and re-check upstream.
+	if t.isNil(data) {
+		return nil, true
 	}
-	return nil, false
+	return data, true
 }
 
 // setting remote cache
-func (t *tCache) setRemote(key string, data []byte, expireIn time.Duration, isForce bool) (ok bool, err error) {
-	var ret string
-	if isForce {
-		ret, err = redis.String(t.m.rdsPool.Get().Do("SET", key, data, "PX", expireIn.Nanoseconds()/1e6))
-	} else {
-		ret, err = redis.String(t.m.rdsPool.Get().Do("SET", key, data, "NX", "PX", expireIn.Nanoseconds()/1e6))
-	}
+func (t *tCache) setRemote(ctx context.Context, key string, data []byte, expireIn time.Duration, isForce bool) (ok bool, err error) {
+	start := time.Now()
+	defer func() { t.option.Stats.OnWrite(layerRemote, key, time.Since(start), err) }()
 
-	if err != nil {
-		return false, err
+	if isForce {
+		ok, err = t.option.Remote.Set(ctx, key, data, expireIn)
+		return ok, err
 	}
-
-	return ret == "OK", nil
+	ok, err = t.option.Remote.SetNX(ctx, key, data, expireIn)
+	return ok, err
 }
 
 // getRemote getting the key's value from remote cache
-func (t *tCache) getRemote(key string) ([]byte, bool, error) {
-	//typ := reflect.TypeOf(obj)
-	//if typ == nil || typ.Kind() != reflect.Ptr {
-	//	return nil, false, fmt.Errorf("can only parse into pointer")
-	//}
-
+func (t *tCache) getRemote(ctx context.Context, key string) ([]byte, bool, error) {
 	//remote mem, the cache for the second layer
-	raw, err := redis.Bytes(t.m.rdsPool.Get().Do("GET", key))
+	raw, err := t.option.Remote.Get(ctx, key)
 	if err != nil {
 		return nil, false, err
 	}
@@ -296,23 +413,41 @@ func (t *tCache) getRemote(key string) ([]byte, bool, error) {
 		return nil, true, nil
 	}
 
-	//r := bytes.NewBuffer(raw)
-	//dec := gob.NewDecoder(r)
-	//err = dec.Decode(&obj)
-	//if err != nil {
-	//	return nil, false, err
-	//}
-
-	return raw, err == nil, err
+	return raw, true, nil
 }
 
 func (t *tCache) purgeLocal(key string) {
 	t.localCache.Delete(key)
 }
 
-func (t *tCache) purgeRemote(key string) {
-	_, e := t.m.rdsPool.Get().Do("DEL", key)
-	if e != nil {
+// ristrettoAvgEntryBytes is the assumed average entry size used to derive a
+// MaxCost budget when only LocalMaxEntries is set, since ristretto rejects a
+// zero MaxCost outright.
+const ristrettoAvgEntryBytes = 1024
+
+// newBoundedLocalStore picks a capacity-bounded LocalStore implementation
+// based on CacheOption's LocalMaxBytes/LocalMaxEntries, matching the repo's
+// existing go-cache behaviour when neither is set. LocalMaxEntries takes the
+// admission-policy backend (ristretto/TinyLFU) regardless of whether
+// LocalMaxBytes is also set, since that's the knob the option doc calls out
+// for bounding a multi-tenant process's memory.
+func newBoundedLocalStore(o *CacheOption) (LocalStore, error) {
+	switch {
+	case o.LocalMaxEntries > 0:
+		maxBytes := o.LocalMaxBytes
+		if maxBytes <= 0 {
+			maxBytes = o.LocalMaxEntries * ristrettoAvgEntryBytes
+		}
+		return NewRistrettoStore(maxBytes, o.LocalMaxEntries)
+	case o.LocalMaxBytes > 0:
+		return NewFreecacheStore(int(o.LocalMaxBytes)), nil
+	default:
+		return NewGoCacheStore(5 * time.Minute), nil
+	}
+}
+
+func (t *tCache) purgeRemote(ctx context.Context, key string) {
+	if e := t.option.Remote.Del(ctx, key); e != nil {
 		fmt.Printf("purgeRemote key=%s, err=%s", key, e)
 	}
 }
@@ -320,8 +455,9 @@ func (t *tCache) purgeRemote(key string) {
 func (t *tCache) watch() {
 	for alteration := range t.watchC {
 		if alteration.oper == commandDel {
-			t.purgeRemote(alteration.key)
+			t.purgeRemote(context.Background(), alteration.key)
 			t.purgeLocal(alteration.key)
+			t.option.Stats.OnEvict(layerLocal|layerRemote, alteration.key)
 		}
 	}
 
@@ -333,58 +469,77 @@ func (t *tCache) IsNil(raw interface{}) bool {
 }
 
 func (t *tCache) Update(ctx context.Context, tag string, argus ...interface{}) error {
-	//notify key to update
-	return t.m.NotifyUpdating(fmt.Sprintf(tag, argus...))
+	// Publish through the RemoteStore adapter rather than the legacy
+	// manager.NotifyUpdating, the same migration Store*/GetOrFetch already
+	// went through in this series: RemoteStore.Publish takes ctx, so
+	// cancellation/deadlines actually reach the Redis driver here too instead
+	// of being dropped at the door.
+	return t.option.Remote.Publish(ctx, fmt.Sprintf(tag, argus...), nil)
 }
 
-func (t *tCache) Store(key string, bdata []byte) error {
-	return t.store(key, bdata, t.option.Layer)
+func (t *tCache) Store(ctx context.Context, key string, bdata []byte) error {
+	return t.store(ctx, key, bdata, t.option.Layer)
 }
-func (t *tCache) StoreLocal(key string, bdata []byte) error {
-	return t.store(key, bdata, layerLocal)
+func (t *tCache) StoreLocal(ctx context.Context, key string, bdata []byte) error {
+	return t.store(ctx, key, bdata, layerLocal)
 }
-func (t *tCache) StoreMem(key string, bdata []byte) error {
-	return t.store(key, bdata, layerRemote)
+func (t *tCache) StoreMem(ctx context.Context, key string, bdata []byte) error {
+	return t.store(ctx, key, bdata, layerRemote)
 }
-func (t *tCache) StoreBoth(key string, bdata []byte) error {
-	return t.store(key, bdata, layerLocal|layerRemote)
+func (t *tCache) StoreBoth(ctx context.Context, key string, bdata []byte) error {
+	return t.store(ctx, key, bdata, layerLocal|layerRemote)
 }
 
-func (t *tCache) GetOrFetch(key string, fetcher fetchFunc, expireIn time.Duration) ([]byte, bool, error) {
+func (t *tCache) GetOrFetch(ctx context.Context, key string, fetcher fetchFunc, expireIn time.Duration) ([]byte, bool, error) {
 	//级联获取
-	data, ok, err := t.getCascade(key, t.option.Layer, true)
+	data, ok, err := t.getCascade(ctx, key, t.option.Layer, true)
 	if err != nil {
 		return nil, false, err
 	}
 	if ok {
+		//命中但临近过期，异步刷新，本次仍返回旧值
+		t.maybeRefreshAhead(ctx, key, fetcher)
 		return data, true, nil
 	}
 
+	//已过期，但仍在 stale-while-revalidate 窗口内，先返回旧值并触发异步刷新
+	if t.option.StaleWhileRevalidate > 0 {
+		if stale, ok := t.getStale(key); ok {
+			t.refreshAsync(ctx, key, fetcher)
+			return stale, true, nil
+		}
+	}
+
 	//loading data from src
-	data, ok, err = t.load(key, fetcher)
+	data, ok, err = t.load(ctx, key, fetcher)
 	if err != nil || !ok {
 		return nil, false, err
 	}
 
-	t.store(key, data, t.option.Layer)
+	t.store(ctx, key, data, t.option.Layer)
+	if t.option.StaleWhileRevalidate > 0 {
+		t.setStale(key, data)
+	}
 
 	return data, true, nil
 }
 
-func (t *tCache) Get(tag string, argus ...interface{}) *tagCache {
+func (t *tCache) Get(ctx context.Context, tag string, argus ...interface{}) *tagCache {
 	return &tagCache{
 		l:   fromCache,
 		key: fmt.Sprintf(tag, argus...),
 		t:   t,
+		ctx: ctx,
 	}
 }
 
-func (t *tCache) OrFetch(fetcher fetchFunc) *tagCache {
+func (t *tCache) OrFetch(ctx context.Context, fetcher fetchFunc) *tagCache {
 	t.fetcher = fetcher
 	return &tagCache{
 		l:   fromSrc,
 		key: "",
 		t:   t,
+		ctx: ctx,
 	}
 }
 
@@ -397,6 +552,7 @@ type tagCache struct {
 	t   *tCache
 	l   int
 	key string
+	ctx context.Context
 }
 
 func (tc *tagCache) Get(tag string, argus ...interface{}) *tagCache {
@@ -425,7 +581,7 @@ func (tc *tagCache) Do() ([]byte, bool, error) {
 	case fromSrc:
 		data, ok, err := tc.load()
 		if err == nil {
-			tc.t.store(tc.key, data, tc.t.option.Layer)
+			tc.t.store(tc.ctx, tc.key, data, tc.t.option.Layer)
 		}
 		return data, ok, err
 	case fromCache | fromSrc:
@@ -433,7 +589,7 @@ func (tc *tagCache) Do() ([]byte, bool, error) {
 		if !ok {
 			data, ok, err = tc.load()
 			if err == nil {
-				tc.t.store(tc.key, data, tc.t.option.Layer)
+				tc.t.store(tc.ctx, tc.key, data, tc.t.option.Layer)
 			}
 		}
 
@@ -449,7 +605,7 @@ func (tc *tagCache) load() ([]byte, bool, error) {
 		return []byte{}, false, nil
 	}
 
-	data, ok, err := tc.t.load(tc.key, tc.t.fetcher)
+	data, ok, err := tc.t.load(tc.ctx, tc.key, tc.t.fetcher)
 	if err != nil || !ok {
 		return nil, false, err
 	}
@@ -459,7 +615,7 @@ func (tc *tagCache) load() ([]byte, bool, error) {
 
 func (tc *tagCache) get() ([]byte, bool, error) {
 	//级联获取
-	data, ok, err := tc.t.getCascade(tc.key, tc.t.option.Layer, true)
+	data, ok, err := tc.t.getCascade(tc.ctx, tc.key, tc.t.option.Layer, true)
 	if err != nil {
 		return nil, false, err
 	}
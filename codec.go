@@ -0,0 +1,128 @@
+package tucana
+
+import (
+	"encoding/json"
+
+	"github.com/bytedance/sonic"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec 序列化层抽象，setLocal（非 []byte 的值）和 GetOrFetchInto 通过它
+// 序列化缓存内容，不再直接依赖 jsoniter。load 不经过 codec，
+// 原样传递 fetcher 返回的字节。
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+	Name() string
+}
+
+// 每种 codec 对应一个固定的一字节标识，写在缓存内容的第一个字节，
+// 这样升级 codec 配置后，旧值依然能按自己当初的 codec 被正确解出来。
+const (
+	codecIDJSON     byte = 1
+	codecIDJSONIter byte = 2
+	codecIDMsgPack  byte = 3
+	codecIDSonic    byte = 4
+	// codecIDCustom tags values written by a user-supplied Codec (anything
+	// passed to WithCodec that isn't one of the four built-ins above). It
+	// carries no entry in codecByID on purpose: decode falls back to
+	// t.option.Codec for it, since the currently configured codec is the
+	// only one that can possibly understand the bytes.
+	codecIDCustom byte = 0
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                               { return "json" }
+
+type jsoniterCodec struct {
+	api jsoniter.API
+}
+
+func (c jsoniterCodec) Marshal(v interface{}) ([]byte, error)      { return c.api.Marshal(v) }
+func (c jsoniterCodec) Unmarshal(data []byte, v interface{}) error { return c.api.Unmarshal(data, v) }
+func (jsoniterCodec) Name() string                                 { return "jsoniter" }
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error)      { return msgpack.Marshal(v) }
+func (msgpackCodec) Unmarshal(data []byte, v interface{}) error { return msgpack.Unmarshal(data, v) }
+func (msgpackCodec) Name() string                               { return "msgpack" }
+
+type sonicCodec struct{}
+
+func (sonicCodec) Marshal(v interface{}) ([]byte, error)      { return sonic.Marshal(v) }
+func (sonicCodec) Unmarshal(data []byte, v interface{}) error { return sonic.Unmarshal(data, v) }
+func (sonicCodec) Name() string                               { return "sonic" }
+
+// DefaultCodec preserves the project's historical jsoniter-based behaviour.
+func DefaultCodec() Codec {
+	return jsoniterCodec{api: jsoniter.ConfigCompatibleWithStandardLibrary}
+}
+
+// NewJSONCodec returns a Codec backed by the standard library's encoding/json.
+func NewJSONCodec() Codec { return jsonCodec{} }
+
+// NewMsgPackCodec returns a Codec backed by vmihailenco/msgpack.
+func NewMsgPackCodec() Codec { return msgpackCodec{} }
+
+// NewSonicCodec returns a Codec backed by bytedance/sonic.
+func NewSonicCodec() Codec { return sonicCodec{} }
+
+var codecByID = map[byte]Codec{
+	codecIDJSON:     jsonCodec{},
+	codecIDJSONIter: DefaultCodec(),
+	codecIDMsgPack:  msgpackCodec{},
+	codecIDSonic:    sonicCodec{},
+}
+
+func codecID(c Codec) byte {
+	switch c.Name() {
+	case "json":
+		return codecIDJSON
+	case "jsoniter":
+		return codecIDJSONIter
+	case "msgpack":
+		return codecIDMsgPack
+	case "sonic":
+		return codecIDSonic
+	default:
+		// A custom Codec plugged in via WithCodec: tag it as "custom"
+		// rather than misattributing it to jsoniter, so decode() routes
+		// it back through the configured codec instead of the wrong one.
+		return codecIDCustom
+	}
+}
+
+// encode marshals v with the configured codec and tags the result with a
+// one-byte codec identifier.
+func (t *tCache) encode(v interface{}) ([]byte, error) {
+	data, err := t.option.Codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{codecID(t.option.Codec)}, data...), nil
+}
+
+// decode reads the leading codec identifier (falling back to the currently
+// configured codec for untagged/custom-tagged bytes) and unmarshals the rest.
+func (t *tCache) decode(raw []byte, dst interface{}) error {
+	if len(raw) == 0 {
+		return t.option.Codec.Unmarshal(raw, dst)
+	}
+
+	if raw[0] == codecIDCustom {
+		return t.option.Codec.Unmarshal(raw[1:], dst)
+	}
+
+	if codec, ok := codecByID[raw[0]]; ok {
+		return codec.Unmarshal(raw[1:], dst)
+	}
+
+	// Untagged data written before this tagging scheme existed: the whole
+	// payload is the configured codec's bytes, with no leading ID to strip.
+	return t.option.Codec.Unmarshal(raw, dst)
+}
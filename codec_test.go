@@ -0,0 +1,75 @@
+package tucana
+
+import "testing"
+
+type codecTestPayload struct {
+	Name string `json:"name"`
+	N    int    `json:"n"`
+}
+
+func TestCodecMarshalUnmarshalRoundTrip(t *testing.T) {
+	codecs := []Codec{
+		NewJSONCodec(),
+		DefaultCodec(),
+		NewMsgPackCodec(),
+		NewSonicCodec(),
+	}
+
+	for _, c := range codecs {
+		c := c
+		t.Run(c.Name(), func(t *testing.T) {
+			in := codecTestPayload{Name: "foo", N: 42}
+			data, err := c.Marshal(in)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+
+			var out codecTestPayload
+			if err := c.Unmarshal(data, &out); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if out != in {
+				t.Errorf("round trip = %+v, want %+v", out, in)
+			}
+		})
+	}
+}
+
+func TestEncodeDecodeTagsCodecID(t *testing.T) {
+	tc := newTestCache(&CacheOption{Codec: NewJSONCodec()})
+	in := codecTestPayload{Name: "bar", N: 7}
+
+	raw, err := tc.encode(in)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if raw[0] != codecIDJSON {
+		t.Errorf("encode tagged byte = %d, want %d", raw[0], codecIDJSON)
+	}
+
+	var out codecTestPayload
+	if err := tc.decode(raw, &out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if out != in {
+		t.Errorf("decode = %+v, want %+v", out, in)
+	}
+}
+
+func TestDecodeFallsBackToConfiguredCodecForUntaggedData(t *testing.T) {
+	tc := newTestCache(&CacheOption{Codec: NewJSONCodec()})
+	in := codecTestPayload{Name: "baz", N: 3}
+
+	data, err := NewJSONCodec().Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var out codecTestPayload
+	if err := tc.decode(data, &out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if out != in {
+		t.Errorf("decode(untagged) = %+v, want %+v", out, in)
+	}
+}
@@ -0,0 +1,153 @@
+package tucana
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// fakeLocalStore is a minimal in-memory LocalStore for tests that need a
+// real tCache without pulling in go-cache/ristretto/freecache.
+type fakeLocalStore struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func newFakeLocalStore() *fakeLocalStore {
+	return &fakeLocalStore{data: map[string][]byte{}}
+}
+
+func (f *fakeLocalStore) Get(key string) ([]byte, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v, ok := f.data[key]
+	return v, ok
+}
+
+func (f *fakeLocalStore) Set(key string, data []byte, expireIn time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data[key] = data
+}
+
+func (f *fakeLocalStore) Delete(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.data, key)
+}
+
+func (f *fakeLocalStore) TTL(key string) (time.Duration, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.data[key]; !ok {
+		return 0, false
+	}
+	return time.Minute, true
+}
+
+// fakeRemoteStore is a minimal in-memory RemoteStore for tests, with real
+// (non-racy) SetNX/MSetNX semantics so NX-loss behavior is exercisable.
+type fakeRemoteStore struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func newFakeRemoteStore() *fakeRemoteStore {
+	return &fakeRemoteStore{data: map[string][]byte{}}
+}
+
+func (f *fakeRemoteStore) Get(ctx context.Context, key string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.data[key], nil
+}
+
+func (f *fakeRemoteStore) Set(ctx context.Context, key string, data []byte, expireIn time.Duration) (bool, error) {
+	// Like the real adapters, refuse to write under a canceled ctx.
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data[key] = data
+	return true, nil
+}
+
+func (f *fakeRemoteStore) SetNX(ctx context.Context, key string, data []byte, expireIn time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, exists := f.data[key]; exists {
+		return false, nil
+	}
+	f.data[key] = data
+	return true, nil
+}
+
+func (f *fakeRemoteStore) Del(ctx context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.data, key)
+	return nil
+}
+
+func (f *fakeRemoteStore) Publish(ctx context.Context, channel string, message []byte) error {
+	return nil
+}
+
+func (f *fakeRemoteStore) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	return make(chan []byte), nil
+}
+
+func (f *fakeRemoteStore) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make(map[string][]byte, len(keys))
+	for _, k := range keys {
+		if v, ok := f.data[k]; ok {
+			out[k] = v
+		}
+	}
+	return out, nil
+}
+
+// put seeds a value directly into the backing store, bypassing NX
+// semantics, for test setup.
+func (f *fakeRemoteStore) put(key string, data []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data[key] = data
+}
+
+func (f *fakeRemoteStore) MSetNX(ctx context.Context, items map[string][]byte, ttls map[string]time.Duration) (map[string]bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make(map[string]bool, len(items))
+	for k, v := range items {
+		if _, exists := f.data[k]; exists {
+			out[k] = false
+			continue
+		}
+		f.data[k] = v
+		out[k] = true
+	}
+	return out, nil
+}
+
+// newFakeCache builds a real, runnable tCache backed by in-memory fakes, no
+// global manager/redis pool required.
+func newFakeCache(opt *CacheOption) *tCache {
+	if opt.Remote == nil {
+		opt.Remote = newFakeRemoteStore()
+	}
+	if opt.Codec == nil {
+		opt.Codec = DefaultCodec()
+	}
+	if opt.Stats == nil {
+		opt.Stats = NewNoopStats()
+	}
+	local := newFakeLocalStore()
+	return &tCache{
+		option:     opt,
+		localCache: local,
+	}
+}
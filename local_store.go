@@ -0,0 +1,15 @@
+package tucana
+
+import "time"
+
+// LocalStore 本地一级缓存的存取抽象，让 tCache 可以在 patrickmn/go-cache、
+// dgraph-io/ristretto (TinyLFU 准入策略) 和 coocood/freecache (环形缓冲区)
+// 之间切换，而不用改动 setLocal/getLocal/purgeLocal 的调用方。
+type LocalStore interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, data []byte, expireIn time.Duration)
+	Delete(key string)
+	// TTL returns the remaining time-to-live for key, used to drive
+	// refresh-ahead. ok is false if the key is absent.
+	TTL(key string) (remaining time.Duration, ok bool)
+}
@@ -0,0 +1,50 @@
+package tucana
+
+import (
+	"time"
+
+	"github.com/coocood/freecache"
+)
+
+// freecacheStore is the LocalStore adapter backed by coocood/freecache, a
+// ring-buffer, mostly off-heap cache sized by a fixed byte budget.
+type freecacheStore struct {
+	c *freecache.Cache
+}
+
+// NewFreecacheStore builds a LocalStore backed by a freecache ring buffer
+// sized to maxBytes.
+func NewFreecacheStore(maxBytes int) LocalStore {
+	return &freecacheStore{c: freecache.NewCache(maxBytes)}
+}
+
+func (s *freecacheStore) Get(key string) ([]byte, bool) {
+	data, err := s.c.Get([]byte(key))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+func (s *freecacheStore) Set(key string, data []byte, expireIn time.Duration) {
+	// freecache's expireSeconds is an integer and treats <=0 as "never
+	// expire", so a sub-second expireIn (e.g. a jittered NegativeTTL) must
+	// round up to 1s rather than truncate to 0.
+	seconds := int((expireIn + time.Second - 1) / time.Second)
+	if seconds <= 0 {
+		seconds = 1
+	}
+	_ = s.c.Set([]byte(key), data, seconds)
+}
+
+func (s *freecacheStore) Delete(key string) {
+	s.c.Del([]byte(key))
+}
+
+func (s *freecacheStore) TTL(key string) (time.Duration, bool) {
+	seconds, err := s.c.TTL([]byte(key))
+	if err != nil || seconds == 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
@@ -0,0 +1,46 @@
+package tucana
+
+import (
+	"time"
+
+	goCache "github.com/patrickmn/go-cache"
+)
+
+// goCacheStore is the LocalStore adapter backed by patrickmn/go-cache, the
+// project's original local backend. It has no admission policy and no
+// memory bound: the map grows until the janitor sweeps expired entries.
+type goCacheStore struct {
+	c *goCache.Cache
+}
+
+// NewGoCacheStore adapts a patrickmn/go-cache instance into a LocalStore.
+func NewGoCacheStore(cleanupInterval time.Duration) LocalStore {
+	return &goCacheStore{c: goCache.New(1*time.Minute, cleanupInterval)}
+}
+
+func (s *goCacheStore) Get(key string) ([]byte, bool) {
+	data, ok := s.c.Get(key)
+	if !ok {
+		return nil, false
+	}
+	return data.([]byte), true
+}
+
+func (s *goCacheStore) Set(key string, data []byte, expireIn time.Duration) {
+	s.c.Set(key, data, expireIn)
+}
+
+func (s *goCacheStore) Delete(key string) {
+	s.c.Delete(key)
+}
+
+func (s *goCacheStore) TTL(key string) (time.Duration, bool) {
+	_, expiration, ok := s.c.GetWithExpiration(key)
+	if !ok {
+		return 0, false
+	}
+	if expiration.IsZero() {
+		return 0, false
+	}
+	return time.Until(expiration), true
+}
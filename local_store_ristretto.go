@@ -0,0 +1,55 @@
+package tucana
+
+import (
+	"time"
+
+	"github.com/dgraph-io/ristretto"
+)
+
+// ristrettoStore is the LocalStore adapter backed by dgraph-io/ristretto,
+// which admits keys via a TinyLFU sketch and evicts under a byte-cost
+// budget instead of growing unboundedly.
+type ristrettoStore struct {
+	c *ristretto.Cache
+}
+
+// NewRistrettoStore builds a LocalStore bounded by maxBytes of cost and
+// maxEntries counters, per ristretto's NumCounters/MaxCost sizing guidance.
+func NewRistrettoStore(maxBytes, maxEntries int64) (LocalStore, error) {
+	if maxEntries <= 0 {
+		maxEntries = 1e6
+	}
+	c, err := ristretto.NewCache(&ristretto.Config{
+		NumCounters: maxEntries * 10,
+		MaxCost:     maxBytes,
+		BufferItems: 64,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ristrettoStore{c: c}, nil
+}
+
+func (s *ristrettoStore) Get(key string) ([]byte, bool) {
+	data, ok := s.c.Get(key)
+	if !ok {
+		return nil, false
+	}
+	return data.([]byte), true
+}
+
+func (s *ristrettoStore) Set(key string, data []byte, expireIn time.Duration) {
+	s.c.SetWithTTL(key, data, int64(len(data)), expireIn)
+}
+
+func (s *ristrettoStore) Delete(key string) {
+	s.c.Del(key)
+}
+
+func (s *ristrettoStore) TTL(key string) (time.Duration, bool) {
+	remaining, ok := s.c.GetTTL(key)
+	if !ok || remaining <= 0 {
+		return 0, false
+	}
+	return remaining, true
+}
@@ -0,0 +1,123 @@
+package tucana
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// BatchFetchFunc resolves the subset of keys that missed both cache tiers.
+type BatchFetchFunc func(missing []string) (map[string][]byte, error)
+
+// GetOrFetchMulti resolves a batch of keys with a single round-trip per
+// tier instead of N sequential GetOrFetch calls: it walks the local cache
+// once, issues one remote MGET for whatever is still missing, then calls
+// fetcher exactly once for the remaining set (coalesced through
+// singleflight, keyed by a hash of the sorted missing set, so concurrent
+// callers with the same missing set share one fetch; batches that merely
+// overlap each fetch on their own) before writing everything back with a
+// single pipelined MSETNX plus local puts. ceiling, if > 0, caps the
+// TTL/jitter this function would otherwise derive from ttlFor.
+func (t *tCache) GetOrFetchMulti(ctx context.Context, keys []string, fetcher BatchFetchFunc, ceiling time.Duration) (map[string][]byte, error) {
+	result := make(map[string][]byte, len(keys))
+
+	//逐个查本地缓存
+	missingLocal := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if bdata, ok := t.getLocal(key); ok {
+			result[key] = bdata
+		} else {
+			missingLocal = append(missingLocal, key)
+		}
+	}
+	if len(missingLocal) == 0 {
+		return result, nil
+	}
+
+	//一次 MGET 补齐远程缓存命中的 key；命中的负缓存哨兵值同样算命中，
+	//不再重新调用 fetcher，和单 key 路径 (getRemote/getCascade) 的行为保持一致，
+	//并且同样写回本地缓存，避免同一个已知缺失的 key 每次都要再打一次 Redis
+	remoteHits, err := t.option.Remote.MGet(ctx, missingLocal)
+	if err != nil {
+		return nil, err
+	}
+
+	missingRemote := make([]string, 0, len(missingLocal))
+	for _, key := range missingLocal {
+		raw, ok := remoteHits[key]
+		if !ok {
+			missingRemote = append(missingRemote, key)
+			continue
+		}
+
+		// raw is already the "_n" sentinel bytes when the key is a known
+		// negative, same representation GetOrFetchMulti's own
+		// fetched-and-still-missing branch below uses for the same state.
+		result[key] = raw
+		t.setLocal(key, raw, t.cappedTTL(raw, ceiling))
+	}
+	if len(missingRemote) == 0 {
+		return result, nil
+	}
+
+	//剩余的 key 合并成一次 fetcher 调用，用 singleflight 按缺失集合去重
+	sort.Strings(missingRemote)
+	fetched, err, _ := t.sf.Do(missingSetKey(missingRemote), func() (interface{}, error) {
+		return fetcher(missingRemote)
+	})
+	if err != nil {
+		return nil, err
+	}
+	fetchedData, _ := fetched.(map[string][]byte)
+
+	//按正/负值分组，各自套用 ttlFor/jitteredTTL（可选地再套用调用方传入的 ceiling 上限）
+	writeBack := make(map[string][]byte, len(missingRemote))
+	ttls := make(map[string]time.Duration, len(missingRemote))
+	for _, key := range missingRemote {
+		bdata, ok := fetchedData[key]
+		if !ok {
+			bdata = t.nil()
+		}
+		result[key] = bdata
+		writeBack[key] = bdata
+		ttls[key] = t.cappedTTL(bdata, ceiling)
+	}
+
+	//一次 MSETNX 写回远程缓存；本地缓存只对赢得 NX 竞争的 key 写入，
+	//和单 key 的 store() 保持一致，避免两层缓存出现分歧
+	setOK, err := t.option.Remote.MSetNX(ctx, writeBack, ttls)
+	if err != nil {
+		return result, err
+	}
+	for key, bdata := range writeBack {
+		if !setOK[key] {
+			continue
+		}
+		t.setLocal(key, bdata, ttls[key])
+	}
+
+	return result, nil
+}
+
+// cappedTTL derives the usual positive/negative jittered TTL for bdata, then
+// clamps it to ceiling when the caller supplied one (ceiling <= 0 means no
+// cap, matching the zero-value convention used by CacheOption's TTL fields).
+func (t *tCache) cappedTTL(bdata []byte, ceiling time.Duration) time.Duration {
+	ttl := t.jitteredTTL(t.ttlFor(bdata))
+	if ceiling > 0 && ttl > ceiling {
+		return ceiling
+	}
+	return ttl
+}
+
+// missingSetKey derives a stable singleflight key from a sorted set of keys.
+func missingSetKey(sortedMissing []string) string {
+	h := sha1.New()
+	for _, key := range sortedMissing {
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("mget:%x", h.Sum(nil))
+}
@@ -0,0 +1,100 @@
+package tucana
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetOrFetchMultiCachesNegativeRemoteHitLocally(t *testing.T) {
+	remote := newFakeRemoteStore()
+	remote.put("missing-key", empty)
+	tc := newFakeCache(&CacheOption{
+		PositiveTTL: time.Minute,
+		NegativeTTL: time.Second,
+		Remote:      remote,
+	})
+
+	fetcherCalls := 0
+	fetcher := func(missing []string) (map[string][]byte, error) {
+		fetcherCalls++
+		return map[string][]byte{}, nil
+	}
+
+	result, err := tc.GetOrFetchMulti(context.Background(), []string{"missing-key"}, fetcher, 0)
+	if err != nil {
+		t.Fatalf("GetOrFetchMulti: %v", err)
+	}
+	if !tc.isNil(result["missing-key"]) {
+		t.Errorf("result[missing-key] = %q, want the negative sentinel", result["missing-key"])
+	}
+	if fetcherCalls != 0 {
+		t.Errorf("fetcher called %d times for an already-known-negative key, want 0", fetcherCalls)
+	}
+
+	if _, ok := tc.getLocal("missing-key"); !ok {
+		t.Error("known-negative remote hit was not written back to the local cache")
+	}
+}
+
+// raceyRemoteStore wraps fakeRemoteStore and seeds a key right after MGet
+// runs, simulating a concurrent writer winning the NX race between this
+// call's own MGet and its later MSetNX.
+type raceyRemoteStore struct {
+	*fakeRemoteStore
+	seedKey string
+	seedVal []byte
+}
+
+func (r *raceyRemoteStore) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	out, err := r.fakeRemoteStore.MGet(ctx, keys)
+	r.fakeRemoteStore.put(r.seedKey, r.seedVal)
+	return out, err
+}
+
+func TestGetOrFetchMultiSkipsLocalWriteWhenMSetNXLosesRace(t *testing.T) {
+	remote := &raceyRemoteStore{
+		fakeRemoteStore: newFakeRemoteStore(),
+		seedKey:         "contested",
+		seedVal:         []byte("someone-elses-value"),
+	}
+	tc := newFakeCache(&CacheOption{
+		PositiveTTL: time.Minute,
+		NegativeTTL: time.Second,
+		Remote:      remote,
+	})
+
+	fetcher := func(missing []string) (map[string][]byte, error) {
+		out := make(map[string][]byte, len(missing))
+		for _, k := range missing {
+			out[k] = []byte("fetched-value")
+		}
+		return out, nil
+	}
+
+	if _, err := tc.GetOrFetchMulti(context.Background(), []string{"contested"}, fetcher, 0); err != nil {
+		t.Fatalf("GetOrFetchMulti: %v", err)
+	}
+
+	if _, ok := tc.getLocal("contested"); ok {
+		t.Error("setLocal was called for a key whose MSetNX lost the NX race")
+	}
+}
+
+func TestGetOrFetchMultiHonorsCeiling(t *testing.T) {
+	tc := newFakeCache(&CacheOption{
+		PositiveTTL: time.Hour,
+		NegativeTTL: time.Second,
+	})
+
+	ceiling := 5 * time.Second
+	got := tc.cappedTTL([]byte("real-value"), ceiling)
+	if got != ceiling {
+		t.Errorf("cappedTTL = %v, want capped to %v", got, ceiling)
+	}
+
+	got = tc.cappedTTL([]byte("real-value"), 0)
+	if got != time.Hour {
+		t.Errorf("cappedTTL with no ceiling = %v, want uncapped %v", got, time.Hour)
+	}
+}
@@ -0,0 +1,27 @@
+package tucana
+
+import "testing"
+
+func TestMissingSetKeyIsDeterministic(t *testing.T) {
+	keys := []string{"a", "b", "c"}
+
+	got1 := missingSetKey(keys)
+	got2 := missingSetKey([]string{"a", "b", "c"})
+	if got1 != got2 {
+		t.Errorf("missingSetKey not deterministic: %q vs %q", got1, got2)
+	}
+}
+
+func TestMissingSetKeyDiffersByContent(t *testing.T) {
+	if missingSetKey([]string{"a", "b"}) == missingSetKey([]string{"a", "c"}) {
+		t.Error("missingSetKey should differ for different key sets")
+	}
+}
+
+func TestMissingSetKeyIsOrderSensitive(t *testing.T) {
+	// callers are expected to sort.Strings before calling missingSetKey,
+	// so an unsorted input must not collide with its sorted counterpart.
+	if missingSetKey([]string{"b", "a"}) == missingSetKey([]string{"a", "b"}) {
+		t.Error("missingSetKey should be sensitive to input order")
+	}
+}
@@ -0,0 +1,83 @@
+package tucana
+
+import "context"
+
+// maybeRefreshAhead kicks off an async repopulation when the cached entry's
+// remaining TTL has dropped below RefreshAheadThreshold, so hot keys never
+// pay the synchronous fetcher cost inside load at the TTL boundary.
+func (t *tCache) maybeRefreshAhead(ctx context.Context, key string, fetcher fetchFunc) {
+	if t.option.RefreshAheadThreshold <= 0 || t.option.PositiveTTL <= 0 {
+		return
+	}
+
+	remaining, ok := t.localCache.TTL(key)
+	if !ok {
+		return
+	}
+
+	if float64(remaining)/float64(t.option.PositiveTTL) > t.option.RefreshAheadThreshold {
+		return
+	}
+
+	t.refreshAsync(ctx, key, fetcher)
+}
+
+// refreshAsync re-runs fetcher under singleflight and writes the result back
+// into the tiers named by Layer, without blocking the caller that triggered it.
+func (t *tCache) refreshAsync(ctx context.Context, key string, fetcher fetchFunc) {
+	go func() {
+		data, err, _ := t.sf.Do("refresh-ahead:"+key, func() (interface{}, error) {
+			data, isNil, err := t.pull(fetcher)
+			if err != nil || isNil {
+				return nil, err
+			}
+			return data, nil
+		})
+		if err != nil || data == nil {
+			return
+		}
+
+		bdata, ok := data.([]byte)
+		if !ok {
+			return
+		}
+
+		// Detached on purpose: by the time this background fetch completes,
+		// the caller that triggered it (GetOrFetch) has very likely already
+		// returned, so its ctx is on borrow time or already Done(). watch()
+		// uses context.Background() for the same reason.
+		// The remote write is a forced SET rather than store's SET NX, since
+		// the value being refreshed is usually still present there.
+		expireIn := t.jitteredTTL(t.ttlFor(bdata))
+		if t.option.Layer&layerRemote != 0 {
+			if _, err := t.setRemote(context.Background(), key, bdata, expireIn, true); err != nil {
+				return
+			}
+		}
+		if t.option.Layer&layerLocal != 0 {
+			t.setLocal(key, bdata, expireIn)
+		}
+		if t.option.StaleWhileRevalidate > 0 {
+			t.setStale(key, bdata)
+		}
+	}()
+}
+
+// setStale mirrors a freshly stored value into a longer-lived shadow cache
+// so a later expired read can still be served instead of blocking on load.
+// staleCache is created once, in WithOptions, rather than lazily here: this
+// runs concurrently from both GetOrFetch callers and refreshAsync's own
+// goroutine, and a lazy nil-check-then-create here would race.
+func (t *tCache) setStale(key string, data []byte) {
+	if t.staleCache == nil {
+		return
+	}
+	t.staleCache.Set(key, data, t.option.PositiveTTL+t.option.StaleWhileRevalidate)
+}
+
+func (t *tCache) getStale(key string) ([]byte, bool) {
+	if t.staleCache == nil {
+		return nil, false
+	}
+	return t.staleCache.Get(key)
+}
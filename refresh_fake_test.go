@@ -0,0 +1,83 @@
+package tucana
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRefreshAsyncDetachedFromCallerContext(t *testing.T) {
+	tc := newFakeCache(&CacheOption{
+		Layer:       layerLocal | layerRemote,
+		PositiveTTL: time.Minute,
+		NegativeTTL: time.Second,
+	})
+
+	// A context that's already canceled by the time refreshAsync's
+	// goroutine runs, mirroring a request-scoped ctx from a caller that has
+	// already returned. If refreshAsync still used this ctx for its
+	// write-back, the fake remote's Set would fail and the local write that
+	// follows it would be skipped instead of populating the cache.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	fetcher := func() ([]byte, bool, error) {
+		defer close(done)
+		return []byte("fresh-value"), false, nil
+	}
+
+	tc.refreshAsync(ctx, "refreshed-key", fetcher)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("refreshAsync's fetcher never ran")
+	}
+
+	// The background write-back happens after the fetcher returns; give the
+	// goroutine a moment to reach t.store.
+	deadline := time.After(time.Second)
+	for {
+		if _, ok := tc.getLocal("refreshed-key"); ok {
+			return
+		}
+		select {
+		case <-deadline:
+			t.Fatal("refreshAsync did not write the refreshed value back despite a canceled caller ctx")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestSetStaleIsNoopWithoutStaleWhileRevalidate(t *testing.T) {
+	tc := newFakeCache(&CacheOption{
+		PositiveTTL: time.Minute,
+		NegativeTTL: time.Second,
+	})
+
+	// staleCache is only created by WithOptions when StaleWhileRevalidate
+	// is enabled; setStale must not panic or lazily create it here.
+	tc.setStale("key", []byte("value"))
+	if _, ok := tc.getStale("key"); ok {
+		t.Error("getStale returned a value despite StaleWhileRevalidate being disabled")
+	}
+}
+
+func TestWithOptionsInitializesStaleCacheOnce(t *testing.T) {
+	tc := newFakeCache(&CacheOption{
+		PositiveTTL: time.Minute,
+		NegativeTTL: time.Second,
+	})
+
+	tc.WithOptions(WithStaleWhileRevalidate(time.Minute))
+	if tc.staleCache == nil {
+		t.Fatal("WithOptions did not initialize staleCache despite StaleWhileRevalidate being set")
+	}
+
+	first := tc.staleCache
+	tc.WithOptions(WithStaleWhileRevalidate(2 * time.Minute))
+	if tc.staleCache != first {
+		t.Error("WithOptions replaced an already-initialized staleCache instead of keeping it")
+	}
+}
@@ -0,0 +1,143 @@
+package tucana
+
+import (
+	"context"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+)
+
+// goRedisStore is the RemoteStore adapter backed by go-redis/v8. It is built
+// on top of goredis.UniversalClient so the same adapter covers single-node,
+// sentinel (failover) and cluster deployments.
+type goRedisStore struct {
+	client goredis.UniversalClient
+}
+
+// NewGoRedisStore adapts a go-redis v8 universal client into a RemoteStore.
+func NewGoRedisStore(client goredis.UniversalClient) RemoteStore {
+	return &goRedisStore{client: client}
+}
+
+// NewGoRedisClusterStore builds a RemoteStore backed by a go-redis cluster client.
+func NewGoRedisClusterStore(opt *goredis.ClusterOptions) RemoteStore {
+	return &goRedisStore{client: goredis.NewClusterClient(opt)}
+}
+
+// NewGoRedisSentinelStore builds a RemoteStore backed by a go-redis sentinel
+// (failover) client.
+func NewGoRedisSentinelStore(opt *goredis.FailoverOptions) RemoteStore {
+	return &goRedisStore{client: goredis.NewFailoverClient(opt)}
+}
+
+func (s *goRedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	raw, err := s.client.Get(ctx, key).Bytes()
+	if err == goredis.Nil {
+		return nil, nil
+	}
+	return raw, err
+}
+
+func (s *goRedisStore) Set(ctx context.Context, key string, data []byte, expireIn time.Duration) (bool, error) {
+	_, err := s.client.Set(ctx, key, data, expireIn).Result()
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *goRedisStore) SetNX(ctx context.Context, key string, data []byte, expireIn time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, key, data, expireIn).Result()
+}
+
+func (s *goRedisStore) Del(ctx context.Context, key string) error {
+	return s.client.Del(ctx, key).Err()
+}
+
+func (s *goRedisStore) Publish(ctx context.Context, channel string, message []byte) error {
+	return s.client.Publish(ctx, channel, message).Err()
+}
+
+func (s *goRedisStore) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	if len(keys) == 0 {
+		return map[string][]byte{}, nil
+	}
+
+	raws, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string][]byte, len(keys))
+	for i, raw := range raws {
+		if raw == nil {
+			continue
+		}
+		switch v := raw.(type) {
+		case string:
+			out[keys[i]] = []byte(v)
+		case []byte:
+			out[keys[i]] = v
+		}
+	}
+	return out, nil
+}
+
+func (s *goRedisStore) MSetNX(ctx context.Context, items map[string][]byte, ttls map[string]time.Duration) (map[string]bool, error) {
+	if len(items) == 0 {
+		return map[string]bool{}, nil
+	}
+
+	cmds := make(map[string]*goredis.BoolCmd, len(items))
+	_, err := s.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for key, data := range items {
+			cmds[key] = pipe.SetNX(ctx, key, data, ttls[key])
+		}
+		return nil
+	})
+	if err != nil && err != goredis.Nil {
+		return nil, err
+	}
+
+	out := make(map[string]bool, len(items))
+	for key, cmd := range cmds {
+		ok, err := cmd.Result()
+		if err != nil && err != goredis.Nil {
+			return nil, err
+		}
+		out[key] = ok
+	}
+	return out, nil
+}
+
+func (s *goRedisStore) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	sub := s.client.Subscribe(ctx, channel)
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, err
+	}
+
+	out := make(chan []byte, 10)
+	go func() {
+		defer sub.Close()
+		defer close(out)
+		ch := sub.Channel()
+		for {
+			select {
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				select {
+				case out <- []byte(msg.Payload):
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,190 @@
+package tucana
+
+import (
+	"context"
+	"github.com/garyburd/redigo/redis"
+	"time"
+)
+
+// redigoStore is the RemoteStore adapter backed by garyburd/redigo.
+// redigo has no native context support, so ctx is only honoured for
+// cancellation/deadline before the connection is taken from the pool.
+type redigoStore struct {
+	pool *redis.Pool
+}
+
+// NewRedigoStore adapts an existing *redis.Pool into a RemoteStore.
+func NewRedigoStore(pool *redis.Pool) RemoteStore {
+	return &redigoStore{pool: pool}
+}
+
+func (s *redigoStore) conn(ctx context.Context) (redis.Conn, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.pool.Get(), nil
+}
+
+func (s *redigoStore) Get(ctx context.Context, key string) ([]byte, error) {
+	conn, err := s.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	raw, err := redis.Bytes(conn.Do("GET", key))
+	if err == redis.ErrNil {
+		return nil, nil
+	}
+	return raw, err
+}
+
+func (s *redigoStore) Set(ctx context.Context, key string, data []byte, expireIn time.Duration) (bool, error) {
+	conn, err := s.conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	ret, err := redis.String(conn.Do("SET", key, data, "PX", expireIn.Nanoseconds()/1e6))
+	if err != nil {
+		return false, err
+	}
+	return ret == "OK", nil
+}
+
+func (s *redigoStore) SetNX(ctx context.Context, key string, data []byte, expireIn time.Duration) (bool, error) {
+	conn, err := s.conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	ret, err := redis.String(conn.Do("SET", key, data, "NX", "PX", expireIn.Nanoseconds()/1e6))
+	if err == redis.ErrNil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return ret == "OK", nil
+}
+
+func (s *redigoStore) Del(ctx context.Context, key string) error {
+	conn, err := s.conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Do("DEL", key)
+	return err
+}
+
+func (s *redigoStore) Publish(ctx context.Context, channel string, message []byte) error {
+	conn, err := s.conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Do("PUBLISH", channel, message)
+	return err
+}
+
+func (s *redigoStore) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	if len(keys) == 0 {
+		return map[string][]byte{}, nil
+	}
+
+	conn, err := s.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	args := make([]interface{}, len(keys))
+	for i, key := range keys {
+		args[i] = key
+	}
+
+	raws, err := redis.ByteSlices(conn.Do("MGET", args...))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string][]byte, len(keys))
+	for i, raw := range raws {
+		if raw != nil {
+			out[keys[i]] = raw
+		}
+	}
+	return out, nil
+}
+
+func (s *redigoStore) MSetNX(ctx context.Context, items map[string][]byte, ttls map[string]time.Duration) (map[string]bool, error) {
+	if len(items) == 0 {
+		return map[string]bool{}, nil
+	}
+
+	conn, err := s.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	keys := make([]string, 0, len(items))
+	for key, data := range items {
+		keys = append(keys, key)
+		px := ttls[key].Nanoseconds() / 1e6
+		if err := conn.Send("SET", key, data, "NX", "PX", px); err != nil {
+			return nil, err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]bool, len(items))
+	for _, key := range keys {
+		ret, err := redis.String(conn.Receive())
+		if err != nil && err != redis.ErrNil {
+			return nil, err
+		}
+		out[key] = ret == "OK"
+	}
+	return out, nil
+}
+
+func (s *redigoStore) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+	conn, err := s.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	psc := redis.PubSubConn{Conn: conn}
+	if err := psc.Subscribe(channel); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	out := make(chan []byte, 10)
+	go func() {
+		defer conn.Close()
+		defer close(out)
+		for {
+			switch v := psc.Receive().(type) {
+			case redis.Message:
+				select {
+				case out <- v.Data:
+				case <-ctx.Done():
+					return
+				}
+			case error:
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,26 @@
+package tucana
+
+import (
+	"context"
+	"time"
+)
+
+// RemoteStore 远程缓存的存取抽象，屏蔽具体的 redis 客户端实现
+// (garyburd/redigo、go-redis/v8 单机/哨兵/集群模式等)，
+// 所有调用都携带 context，便于取消和超时控制向下传递。
+type RemoteStore interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, data []byte, expireIn time.Duration) (bool, error)
+	SetNX(ctx context.Context, key string, data []byte, expireIn time.Duration) (bool, error)
+	Del(ctx context.Context, key string) error
+	Publish(ctx context.Context, channel string, message []byte) error
+	Subscribe(ctx context.Context, channel string) (<-chan []byte, error)
+
+	// MGet fetches several keys in one round-trip (pipelined GETs on
+	// cluster backends). Missing keys are simply absent from the result.
+	MGet(ctx context.Context, keys []string) (map[string][]byte, error)
+	// MSetNX writes several keys in one pipelined round-trip, each with its
+	// own TTL (ttls must contain every key in items), mirroring the NX
+	// semantics of SetNX per key.
+	MSetNX(ctx context.Context, items map[string][]byte, ttls map[string]time.Duration) (map[string]bool, error)
+}
@@ -0,0 +1,37 @@
+package tucana
+
+import "time"
+
+// Stats is the observability hook for the two cache tiers. It is wired into
+// getCascade (hit/miss per layer), load (upstream fetcher latency/errors),
+// setLocal/setRemote (per-layer write latency/errors, kept separate from
+// fetcher latency so a slowdown can be attributed to Redis vs. the
+// upstream source vs. singleflight contention), and watch (evictions
+// driven by update notifications). The default is a no-op so existing
+// callers see zero overhead until they opt in.
+type Stats interface {
+	OnHit(layer int, key string)
+	OnMiss(layer int, key string)
+	OnFetch(key string, dur time.Duration, err error)
+	OnWrite(layer int, key string, dur time.Duration, err error)
+	OnEvict(layer int, key string)
+}
+
+type noopStats struct{}
+
+func (noopStats) OnHit(layer int, key string)                                {}
+func (noopStats) OnMiss(layer int, key string)                               {}
+func (noopStats) OnFetch(key string, dur time.Duration, err error)           {}
+func (noopStats) OnWrite(layer int, key string, dur time.Duration, err error) {}
+func (noopStats) OnEvict(layer int, key string)                              {}
+
+// NewNoopStats returns a Stats implementation that does nothing.
+func NewNoopStats() Stats { return noopStats{} }
+
+func (t *tCache) reportHitMiss(layer int, key string, hit bool) {
+	if hit {
+		t.option.Stats.OnHit(layer, key)
+		return
+	}
+	t.option.Stats.OnMiss(layer, key)
+}
@@ -0,0 +1,70 @@
+package tucana
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingStats records every call it receives, for assertions in tests
+// that exercise a real tCache end to end.
+type recordingStats struct {
+	mu     sync.Mutex
+	writes []int // layers passed to OnWrite
+}
+
+func (s *recordingStats) OnHit(layer int, key string)  {}
+func (s *recordingStats) OnMiss(layer int, key string) {}
+func (s *recordingStats) OnFetch(key string, dur time.Duration, err error) {}
+func (s *recordingStats) OnWrite(layer int, key string, dur time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writes = append(s.writes, layer)
+}
+func (s *recordingStats) OnEvict(layer int, key string) {}
+
+func (s *recordingStats) sawWrite(layer int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, l := range s.writes {
+		if l == layer {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSetLocalReportsWriteStats(t *testing.T) {
+	stats := &recordingStats{}
+	tc := newFakeCache(&CacheOption{
+		Layer:       layerLocal,
+		PositiveTTL: time.Minute,
+		NegativeTTL: time.Second,
+		Stats:       stats,
+	})
+
+	tc.setLocal("key", []byte("value"), time.Minute)
+
+	if !stats.sawWrite(layerLocal) {
+		t.Error("setLocal did not report an OnWrite(layerLocal, ...) call")
+	}
+}
+
+func TestSetRemoteReportsWriteStats(t *testing.T) {
+	stats := &recordingStats{}
+	tc := newFakeCache(&CacheOption{
+		Layer:       layerRemote,
+		PositiveTTL: time.Minute,
+		NegativeTTL: time.Second,
+		Stats:       stats,
+	})
+
+	if _, err := tc.setRemote(context.Background(), "key", []byte("value"), time.Minute, false); err != nil {
+		t.Fatalf("setRemote: %v", err)
+	}
+
+	if !stats.sawWrite(layerRemote) {
+		t.Error("setRemote did not report an OnWrite(layerRemote, ...) call")
+	}
+}
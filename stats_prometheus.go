@@ -0,0 +1,92 @@
+package tucana
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// promStats is the built-in Prometheus Stats adapter. Metrics are labeled
+// by layer only, not by key: per-key cardinality would blow up Prometheus
+// on any cache with a large or dynamic keyspace.
+type promStats struct {
+	hits         *prometheus.CounterVec
+	misses       *prometheus.CounterVec
+	evicts       *prometheus.CounterVec
+	fetches      *prometheus.CounterVec
+	latency      *prometheus.HistogramVec
+	writes       *prometheus.CounterVec
+	writeLatency *prometheus.HistogramVec
+}
+
+// NewPrometheusStats registers and returns a Stats implementation backed by
+// Prometheus counters/histograms, namespaced under "tucana_cache_".
+func NewPrometheusStats(reg prometheus.Registerer) Stats {
+	s := &promStats{
+		hits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tucana_cache_hits_total",
+			Help: "Cache hits per layer.",
+		}, []string{"layer"}),
+		misses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tucana_cache_misses_total",
+			Help: "Cache misses per layer.",
+		}, []string{"layer"}),
+		evicts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tucana_cache_evictions_total",
+			Help: "Cache evictions per layer.",
+		}, []string{"layer"}),
+		fetches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tucana_cache_fetches_total",
+			Help: "Upstream fetch/round-trip attempts, labeled by outcome.",
+		}, []string{"outcome"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "tucana_cache_fetch_duration_seconds",
+			Help:    "Upstream fetch/round-trip latency.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"outcome"}),
+		writes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tucana_cache_writes_total",
+			Help: "Cache writes per layer, labeled by outcome.",
+		}, []string{"layer", "outcome"}),
+		writeLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "tucana_cache_write_duration_seconds",
+			Help:    "Cache write latency per layer, distinct from upstream fetch latency.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"layer", "outcome"}),
+	}
+
+	reg.MustRegister(s.hits, s.misses, s.evicts, s.fetches, s.latency, s.writes, s.writeLatency)
+	return s
+}
+
+func (s *promStats) OnHit(layer int, key string) {
+	s.hits.WithLabelValues(strconv.Itoa(layer)).Inc()
+}
+
+func (s *promStats) OnMiss(layer int, key string) {
+	s.misses.WithLabelValues(strconv.Itoa(layer)).Inc()
+}
+
+func (s *promStats) OnFetch(key string, dur time.Duration, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "err"
+	}
+	s.fetches.WithLabelValues(outcome).Inc()
+	s.latency.WithLabelValues(outcome).Observe(dur.Seconds())
+}
+
+func (s *promStats) OnWrite(layer int, key string, dur time.Duration, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "err"
+	}
+	layerLabel := strconv.Itoa(layer)
+	s.writes.WithLabelValues(layerLabel, outcome).Inc()
+	s.writeLatency.WithLabelValues(layerLabel, outcome).Observe(dur.Seconds())
+}
+
+func (s *promStats) OnEvict(layer int, key string) {
+	s.evicts.WithLabelValues(strconv.Itoa(layer)).Inc()
+}
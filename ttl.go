@@ -0,0 +1,29 @@
+package tucana
+
+import (
+	"math/rand"
+	"time"
+)
+
+// ttlFor picks PositiveTTL or NegativeTTL depending on whether bdata is the
+// "_n" not-found sentinel, so a brief upstream miss doesn't get cached for
+// as long as a real value would.
+func (t *tCache) ttlFor(bdata []byte) time.Duration {
+	if t.isNil(bdata) {
+		return t.option.NegativeTTL
+	}
+	return t.option.PositiveTTL
+}
+
+// jitteredTTL adds a uniform +/- TTLJitter fraction to base so keys cached
+// in the same second don't all expire in the same second (the classic
+// cache-stampede mitigation).
+func (t *tCache) jitteredTTL(base time.Duration) time.Duration {
+	if t.option.TTLJitter <= 0 {
+		return base
+	}
+
+	delta := float64(base) * t.option.TTLJitter
+	offset := (rand.Float64()*2 - 1) * delta
+	return base + time.Duration(offset)
+}
@@ -0,0 +1,44 @@
+package tucana
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestCache(opt *CacheOption) *tCache {
+	return &tCache{option: opt}
+}
+
+func TestTTLForSelectsPositiveOrNegative(t *testing.T) {
+	tc := newTestCache(&CacheOption{
+		PositiveTTL: 10 * time.Minute,
+		NegativeTTL: time.Second,
+	})
+
+	if got := tc.ttlFor([]byte("hello")); got != 10*time.Minute {
+		t.Errorf("ttlFor(real value) = %v, want %v", got, 10*time.Minute)
+	}
+	if got := tc.ttlFor(empty); got != time.Second {
+		t.Errorf("ttlFor(negative sentinel) = %v, want %v", got, time.Second)
+	}
+}
+
+func TestJitteredTTLNoJitter(t *testing.T) {
+	tc := newTestCache(&CacheOption{TTLJitter: 0})
+	if got := tc.jitteredTTL(time.Minute); got != time.Minute {
+		t.Errorf("jitteredTTL with TTLJitter=0 = %v, want %v unchanged", got, time.Minute)
+	}
+}
+
+func TestJitteredTTLWithinBounds(t *testing.T) {
+	tc := newTestCache(&CacheOption{TTLJitter: 0.2})
+	base := time.Minute
+	delta := time.Duration(float64(base) * 0.2)
+
+	for i := 0; i < 1000; i++ {
+		got := tc.jitteredTTL(base)
+		if got < base-delta || got > base+delta {
+			t.Fatalf("jitteredTTL(%v) = %v, want within [%v, %v]", base, got, base-delta, base+delta)
+		}
+	}
+}
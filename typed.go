@@ -0,0 +1,41 @@
+package tucana
+
+import "context"
+
+// TypedFetchFunc resolves the source-of-truth value for GetOrFetchInto. It
+// returns the value itself (not pre-serialized bytes) so it can be routed
+// through the configured Codec like everything else on this entry point.
+type TypedFetchFunc func() (value interface{}, isEmpty bool, err error)
+
+// GetOrFetchInto is the typed counterpart of GetOrFetch: it serializes with
+// the configured Codec on the way in and decodes straight into dst on the
+// way out, instead of handing the caller raw bytes.
+func (t *tCache) GetOrFetchInto(ctx context.Context, key string, dst interface{}, fetcher TypedFetchFunc) (bool, error) {
+	data, ok, err := t.getCascade(ctx, key, t.option.Layer, true)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		// getLocal/getRemote report a known-negative hit as (nil, true).
+		if len(data) == 0 || t.isNil(data) {
+			return false, nil
+		}
+		return true, t.decode(data, dst)
+	}
+
+	data, ok, err = t.load(ctx, key, func() ([]byte, bool, error) {
+		value, isEmpty, err := fetcher()
+		if err != nil || isEmpty {
+			return nil, isEmpty, err
+		}
+		bdata, err := t.encode(value)
+		return bdata, false, err
+	})
+	if err != nil || !ok {
+		return false, err
+	}
+
+	t.store(ctx, key, data, t.option.Layer)
+
+	return true, t.decode(data, dst)
+}